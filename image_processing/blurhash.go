@@ -0,0 +1,250 @@
+package imageprocessing
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+// base83Chars is the alphabet BlurHash uses to pack its coefficients into a
+// short ASCII string.
+const base83Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// BlurHash computes a compact placeholder-thumbnail hash for img, matching
+// the format popularized by woltapp/blurhash (and used by GoToSocial for
+// image placeholders). xComponents and yComponents control how many DCT
+// basis functions are sampled along each axis and must be between 1 and 9;
+// larger values capture more detail at the cost of a longer hash.
+func BlurHash(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", fmt.Errorf("blurhash: components must be between 1 and 9, got %dx%d", xComponents, yComponents)
+	}
+	if img == nil {
+		return "", fmt.Errorf("blurhash: nil image")
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return "", fmt.Errorf("blurhash: image has zero width or height: %v", bounds)
+	}
+	factors := make([][3]float64, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1.0
+			}
+			factors[j*xComponents+i] = multiplyBasisFunction(img, bounds, i, j, normalisation)
+		}
+	}
+
+	dc := factors[0]
+	ac := factors[1:]
+
+	var hash strings.Builder
+	hash.WriteString(base83Encode((xComponents-1)+(yComponents-1)*9, 1))
+
+	maximumValue := 1.0
+	if len(ac) > 0 {
+		actualMax := 0.0
+		for _, f := range ac {
+			actualMax = math.Max(actualMax, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maximumValue = float64(quantisedMax+1) / 166
+		hash.WriteString(base83Encode(quantisedMax, 1))
+	} else {
+		hash.WriteString(base83Encode(0, 1))
+	}
+
+	hash.WriteString(base83Encode(encodeDC(dc), 4))
+	for _, f := range ac {
+		hash.WriteString(base83Encode(encodeAC(f, maximumValue), 2))
+	}
+
+	return hash.String(), nil
+}
+
+// DecodeBlurHash reconstructs a low-frequency width x height image from a
+// hash produced by BlurHash. punch scales the AC (detail) coefficients;
+// values above 1 exaggerate contrast. Values below 1 are clamped to 1,
+// since punch is integral there is no way to flatten contrast below the
+// hash's original values.
+func DecodeBlurHash(hash string, width, height, punch int) (image.Image, error) {
+	if len(hash) < 6 {
+		return nil, fmt.Errorf("blurhash: hash %q is too short", hash)
+	}
+	if punch < 1 {
+		punch = 1
+	}
+
+	sizeFlag := base83Decode(hash[0:1])
+	numX := sizeFlag%9 + 1
+	numY := sizeFlag/9 + 1
+
+	expectedLen := 4 + 2*numX*numY
+	if len(hash) != expectedLen {
+		return nil, fmt.Errorf("blurhash: expected a %d character hash for a %dx%d hash, got %d", expectedLen, numX, numY, len(hash))
+	}
+
+	quantisedMax := base83Decode(hash[1:2])
+	maximumValue := float64(quantisedMax+1) / 166 * float64(punch)
+
+	colors := make([][3]float64, numX*numY)
+	colors[0] = decodeDC(base83Decode(hash[2:6]))
+	for i := 1; i < len(colors); i++ {
+		start := 4 + i*2
+		colors[i] = decodeAC(base83Decode(hash[start:start+2]), maximumValue)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var r, g, b float64
+			for j := 0; j < numY; j++ {
+				for i := 0; i < numX; i++ {
+					basis := math.Cos(math.Pi*float64(x)*float64(i)/float64(width)) * math.Cos(math.Pi*float64(y)*float64(j)/float64(height))
+					c := colors[j*numX+i]
+					r += c[0] * basis
+					g += c[1] * basis
+					b += c[2] * basis
+				}
+			}
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(linearToSrgb(r)),
+				G: uint8(linearToSrgb(g)),
+				B: uint8(linearToSrgb(b)),
+				A: 255,
+			})
+		}
+	}
+	return img, nil
+}
+
+// multiplyBasisFunction projects img's linear-sRGB channels onto the (i, j)
+// cosine basis function, returning the per-channel coefficient.
+func multiplyBasisFunction(img image.Image, bounds image.Rectangle, i, j int, normalisation float64) [3]float64 {
+	width, height := bounds.Dx(), bounds.Dy()
+	var r, g, b float64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) * math.Cos(math.Pi*float64(j)*float64(y)/float64(height))
+			cr, cg, cb, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			r += basis * srgbToLinear(uint8(cr>>8))
+			g += basis * srgbToLinear(uint8(cg>>8))
+			b += basis * srgbToLinear(uint8(cb>>8))
+		}
+	}
+	scale := normalisation / float64(width*height)
+	return [3]float64{r * scale, g * scale, b * scale}
+}
+
+// encodeDC packs the DC (average color) coefficient into a 24-bit integer,
+// encoded as 4 base83 digits.
+func encodeDC(value [3]float64) int {
+	r := linearToSrgb(value[0])
+	g := linearToSrgb(value[1])
+	b := linearToSrgb(value[2])
+	return (r << 16) + (g << 8) + b
+}
+
+// encodeAC quantizes an AC coefficient to 19 levels per channel, encoded as
+// 2 base83 digits.
+func encodeAC(value [3]float64, maximumValue float64) int {
+	quantR := clampInt(int(math.Floor(signPow(value[0]/maximumValue, 0.5)*9+9.5)), 0, 18)
+	quantG := clampInt(int(math.Floor(signPow(value[1]/maximumValue, 0.5)*9+9.5)), 0, 18)
+	quantB := clampInt(int(math.Floor(signPow(value[2]/maximumValue, 0.5)*9+9.5)), 0, 18)
+	return quantR*19*19 + quantG*19 + quantB
+}
+
+// decodeDC unpacks a DC coefficient produced by encodeDC.
+func decodeDC(value int) [3]float64 {
+	r := value >> 16
+	g := (value >> 8) & 255
+	b := value & 255
+	return [3]float64{srgbToLinear(uint8(r)), srgbToLinear(uint8(g)), srgbToLinear(uint8(b))}
+}
+
+// decodeAC unpacks an AC coefficient produced by encodeAC.
+func decodeAC(value int, maximumValue float64) [3]float64 {
+	quantR := value / (19 * 19)
+	quantG := (value / 19) % 19
+	quantB := value % 19
+	return [3]float64{
+		signPow((float64(quantR)-9)/9, 2) * maximumValue,
+		signPow((float64(quantG)-9)/9, 2) * maximumValue,
+		signPow((float64(quantB)-9)/9, 2) * maximumValue,
+	}
+}
+
+// srgbToLinear converts an 8-bit sRGB channel value to linear light.
+func srgbToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSrgb converts a linear-light channel value back to an 8-bit sRGB
+// value, clamping to [0, 255].
+func linearToSrgb(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	var srgb float64
+	if v <= 0.0031308 {
+		srgb = v * 12.92 * 255
+	} else {
+		srgb = (1.055*math.Pow(v, 1/2.4) - 0.055) * 255
+	}
+	return int(clamp8(int(math.Round(srgb))))
+}
+
+// signPow raises the magnitude of value to exp while preserving its sign.
+func signPow(value, exp float64) float64 {
+	sign := 1.0
+	if value < 0 {
+		sign = -1.0
+	}
+	return sign * math.Pow(math.Abs(value), exp)
+}
+
+// clampInt clamps v to [min, max].
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// base83Encode encodes value as a base83 string of exactly length digits.
+func base83Encode(value, length int) string {
+	digits := make([]byte, length)
+	for i := 1; i <= length; i++ {
+		digit := (value / intPow(83, length-i)) % 83
+		digits[i-1] = base83Chars[digit]
+	}
+	return string(digits)
+}
+
+// base83Decode decodes a base83-encoded string back into an integer.
+func base83Decode(s string) int {
+	value := 0
+	for _, c := range s {
+		value = value*83 + strings.IndexRune(base83Chars, c)
+	}
+	return value
+}
+
+// intPow returns base raised to a non-negative integer exponent.
+func intPow(base, exp int) int {
+	result := 1
+	for i := 0; i < exp; i++ {
+		result *= base
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package imageprocessing
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestBlurHashProducesExpectedLength(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 30), G: uint8(y * 30), B: 100, A: 255})
+		}
+	}
+
+	hash, err := BlurHash(img, 4, 3)
+	if err != nil {
+		t.Fatalf("BlurHash failed: %v", err)
+	}
+
+	wantLen := 4 + 2*4*3
+	if len(hash) != wantLen {
+		t.Errorf("Expected hash length %d, got %d (%s)", wantLen, len(hash), hash)
+	}
+}
+
+func TestBlurHashRejectsInvalidComponents(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+
+	if _, err := BlurHash(img, 0, 3); err == nil {
+		t.Errorf("Expected an error for xComponents below 1")
+	}
+	if _, err := BlurHash(img, 4, 10); err == nil {
+		t.Errorf("Expected an error for yComponents above 9")
+	}
+}
+
+func TestBlurHashRejectsZeroSizeImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 0, 5))
+
+	if _, err := BlurHash(img, 3, 3); err == nil {
+		t.Errorf("Expected an error for a zero-width image")
+	}
+}
+
+func TestDecodeBlurHashRoundTripsFlatColor(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	hash, err := BlurHash(img, 3, 3)
+	if err != nil {
+		t.Fatalf("BlurHash failed: %v", err)
+	}
+
+	decoded, err := DecodeBlurHash(hash, 4, 4, 1)
+	if err != nil {
+		t.Fatalf("DecodeBlurHash failed: %v", err)
+	}
+
+	r, g, b, _ := decoded.At(2, 2).RGBA()
+	if diff := int(r>>8) - 200; diff > 10 || diff < -10 {
+		t.Errorf("Expected decoded R near 200, got %d", r>>8)
+	}
+	if diff := int(g>>8) - 100; diff > 10 || diff < -10 {
+		t.Errorf("Expected decoded G near 100, got %d", g>>8)
+	}
+	if diff := int(b>>8) - 50; diff > 10 || diff < -10 {
+		t.Errorf("Expected decoded B near 50, got %d", b>>8)
+	}
+}
+
+func TestDecodeBlurHashRejectsShortHash(t *testing.T) {
+	if _, err := DecodeBlurHash("abc", 4, 4, 1); err == nil {
+		t.Errorf("Expected an error for a too-short hash")
+	}
+}
+
+func TestBase83RoundTrip(t *testing.T) {
+	for _, v := range []int{0, 1, 82, 100, 6889} {
+		encoded := base83Encode(v, 4)
+		if strings.TrimSpace(encoded) == "" {
+			t.Fatalf("base83Encode(%d) produced an empty string", v)
+		}
+		if decoded := base83Decode(encoded); decoded != v {
+			t.Errorf("base83 round trip failed for %d: got %d", v, decoded)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package imageprocessing
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func flatRGBAMatrix(width, height int, value float64) RGBAMatrix {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(value)
+			img.Set(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	return ImageToRGBAMatrix(img)
+}
+
+func TestConvolveIdentityOnFlatImage(t *testing.T) {
+	m := flatRGBAMatrix(5, 5, 100)
+	identity := Kernel{Width: 3, Height: 3, Data: [][]float64{
+		{0, 0, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	}}
+
+	out := m.Convolve(identity, ConvolveOpts{Border: BorderClamp})
+	p := out.Data[2][2]
+	if p.R != 100 || p.G != 100 || p.B != 100 {
+		t.Errorf("Expected identity kernel to preserve pixel value, got %+v", p)
+	}
+}
+
+func TestSobelFlatImageHasNoGradient(t *testing.T) {
+	m := flatRGBAMatrix(5, 5, 100)
+	field := m.Sobel(ConvolveOpts{Border: BorderClamp})
+
+	if field.Magnitude[2][2] != 0 {
+		t.Errorf("Expected zero gradient magnitude on a flat image, got %.2f", field.Magnitude[2][2])
+	}
+}
+
+func TestEdgeDetectFindsStepEdge(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 6, 6))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			v := uint8(0)
+			if x >= 3 {
+				v = 255
+			}
+			img.Set(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	m := ImageToRGBAMatrix(img)
+
+	edges := m.EdgeDetect(100, ConvolveOpts{Border: BorderClamp})
+	if edges.GrayAt(3, 3).Y == 0 {
+		t.Errorf("Expected an edge to be detected at the step boundary")
+	}
+	if edges.GrayAt(0, 0).Y != 0 {
+		t.Errorf("Expected no edge in a flat region")
+	}
+}
+
+func TestUnsharpMaskNoOpBelowThreshold(t *testing.T) {
+	m := flatRGBAMatrix(7, 7, 128)
+	out := m.UnsharpMask(2.0, 1.0, 10.0, ConvolveOpts{Border: BorderClamp})
+
+	p := out.Data[3][3]
+	if p.R != 128 {
+		t.Errorf("Expected unsharp mask to leave a flat region unchanged, got %.2f", p.R)
+	}
+}
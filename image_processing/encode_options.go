@@ -0,0 +1,97 @@
+package imageprocessing
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chai2010/webp"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// defaultJPEGQuality and defaultWebPQuality are used in place of a zero
+// EncodeOptions.JPEGQuality/WebPQuality. defaultJPEGQuality matches the
+// quality WriteImage gets from passing nil JPEG options; WriteImage does
+// not write WebP at all. Without this, the zero value
+// would be taken literally and clamped by the underlying encoders to
+// their near-worst quality setting.
+const (
+	defaultJPEGQuality = 75
+	defaultWebPQuality = 90
+)
+
+// EncodeOptions configures the format-specific settings WriteImageWith
+// passes to the underlying encoder. Only the fields relevant to the format
+// actually written are used.
+type EncodeOptions struct {
+	// JPEGQuality is passed straight to image/jpeg, which auto-selects
+	// 4:2:0 vs. 4:4:4 chroma subsampling based on this value. Zero means
+	// defaultJPEGQuality, the same quality WriteImage uses.
+	JPEGQuality int
+	// PNGCompression selects a png.Encoder compression level. The zero
+	// value is png.DefaultCompression.
+	PNGCompression png.CompressionLevel
+	// WebPQuality is the WebP quality factor in [0, 100], ignored when
+	// WebPLossless is set. Zero means defaultWebPQuality.
+	WebPQuality float32
+	// WebPLossless requests lossless WebP encoding.
+	WebPLossless bool
+	// TIFFCompression selects a tiff.CompressionType.
+	TIFFCompression tiff.CompressionType
+	// GIFNumColors caps the size of the palette image/gif quantizes to.
+	GIFNumColors int
+}
+
+// WriteImageWith writes img to path using format-specific encoder settings
+// from opts. The format is inferred from path's extension, the same way
+// WriteImage infers it when no format argument is given.
+func WriteImageWith(path string, img image.Image, opts EncodeOptions) error {
+	if img == nil {
+		return fmt.Errorf("no image data to write: %s", path)
+	}
+
+	outFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", path, err)
+	}
+	defer outFile.Close()
+
+	f := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+
+	jpegQuality := opts.JPEGQuality
+	if jpegQuality == 0 {
+		jpegQuality = defaultJPEGQuality
+	}
+	webPQuality := opts.WebPQuality
+	if webPQuality == 0 {
+		webPQuality = defaultWebPQuality
+	}
+
+	switch f {
+	case "jpg", "jpeg":
+		err = jpeg.Encode(outFile, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		err = (&png.Encoder{CompressionLevel: opts.PNGCompression}).Encode(outFile, img)
+	case "webp":
+		err = webp.Encode(outFile, img, &webp.Options{Lossless: opts.WebPLossless, Quality: webPQuality})
+	case "tiff", "tif":
+		err = tiff.Encode(outFile, img, &tiff.Options{Compression: opts.TIFFCompression})
+	case "bmp":
+		err = bmp.Encode(outFile, img)
+	case "gif":
+		err = gif.Encode(outFile, img, &gif.Options{NumColors: opts.GIFNumColors})
+	default:
+		err = fmt.Errorf("unsupported image format: %s", f)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to encode image %s: %w", path, err)
+	}
+	return nil
+}
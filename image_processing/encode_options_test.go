@@ -0,0 +1,86 @@
+package imageprocessing
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteImageWithInfersFormatFromExtension(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 100, B: 50, A: 255})
+		}
+	}
+
+	cases := []struct {
+		name string
+		opts EncodeOptions
+	}{
+		{"out.png", EncodeOptions{PNGCompression: -1}},
+		{"out.jpg", EncodeOptions{JPEGQuality: 85}},
+		{"out.gif", EncodeOptions{GIFNumColors: 256}},
+	}
+
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), c.name)
+		if err := WriteImageWith(path, img, c.opts); err != nil {
+			t.Errorf("WriteImageWith(%s) failed: %v", c.name, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+}
+
+func TestWriteImageWithZeroQualityUsesDefault(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 4), B: 100, A: 255})
+		}
+	}
+
+	dir := t.TempDir()
+	defaultPath := filepath.Join(dir, "default.jpg")
+	lowPath := filepath.Join(dir, "low.jpg")
+
+	if err := WriteImageWith(defaultPath, img, EncodeOptions{}); err != nil {
+		t.Fatalf("WriteImageWith with zero-value options failed: %v", err)
+	}
+	if err := WriteImageWith(lowPath, img, EncodeOptions{JPEGQuality: 1}); err != nil {
+		t.Fatalf("WriteImageWith with explicit low quality failed: %v", err)
+	}
+
+	defaultInfo, err := os.Stat(defaultPath)
+	if err != nil {
+		t.Fatalf("stat default.jpg: %v", err)
+	}
+	lowInfo, err := os.Stat(lowPath)
+	if err != nil {
+		t.Fatalf("stat low.jpg: %v", err)
+	}
+
+	if defaultInfo.Size() <= lowInfo.Size() {
+		t.Errorf("zero-value JPEGQuality produced a file no larger than explicit quality 1 (%d <= %d bytes); zero value is not being defaulted", defaultInfo.Size(), lowInfo.Size())
+	}
+}
+
+func TestWriteImageWithRejectsUnsupportedFormat(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	path := filepath.Join(t.TempDir(), "out.svg")
+
+	if err := WriteImageWith(path, img, EncodeOptions{}); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
+
+func TestWriteImageWithNilImage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.png")
+	if err := WriteImageWith(path, nil, EncodeOptions{}); err == nil {
+		t.Errorf("expected an error when writing a nil image")
+	}
+}
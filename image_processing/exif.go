@@ -0,0 +1,108 @@
+package imageprocessing
+
+import (
+	"fmt"
+	"image"
+	"os"
+
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// Metadata holds the EXIF fields ReadImageWithMeta extracts from a JPEG's
+// APP1 segment, if present.
+type Metadata struct {
+	// Orientation is the raw EXIF Orientation tag value (1-8) that was
+	// applied to the returned image; it is always 1 if no tag was found.
+	Orientation  int
+	DateTime     string
+	CameraModel  string
+	HasGPS       bool
+	GPSLatitude  float64
+	GPSLongitude float64
+}
+
+// ReadImageWithMeta reads an image from path like ReadImage, but also parses
+// its EXIF metadata (if present) and rotates/flips the image according to
+// the Orientation tag, so JPEGs from phones come back displayed right-side
+// up instead of however the sensor happened to be held.
+func ReadImageWithMeta(path string) (image.Image, *Metadata, error) {
+	inputFile, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open image: %s: %w", path, err)
+	}
+	defer inputFile.Close()
+
+	img, _, err := image.Decode(inputFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode image: %s: %w", path, err)
+	}
+	img = ToNRGBA(img)
+
+	meta := readMetadata(path)
+	if meta != nil && meta.Orientation != 1 {
+		img = applyOrientation(img, meta.Orientation)
+	}
+	return img, meta, nil
+}
+
+// readMetadata parses EXIF fields from path, returning nil if the file has
+// no EXIF segment (e.g. PNG, or a JPEG without camera metadata).
+func readMetadata(path string) *Metadata {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	x, err := exif.Decode(f)
+	if err != nil {
+		return nil
+	}
+
+	meta := &Metadata{Orientation: 1}
+	if tag, err := x.Get(exif.Orientation); err == nil {
+		if v, err := tag.Int(0); err == nil {
+			meta.Orientation = v
+		}
+	}
+	if tag, err := x.Get(exif.DateTime); err == nil {
+		if v, err := tag.StringVal(); err == nil {
+			meta.DateTime = v
+		}
+	}
+	if tag, err := x.Get(exif.Model); err == nil {
+		if v, err := tag.StringVal(); err == nil {
+			meta.CameraModel = v
+		}
+	}
+	if lat, lon, err := x.LatLong(); err == nil {
+		meta.HasGPS = true
+		meta.GPSLatitude = lat
+		meta.GPSLongitude = lon
+	}
+	return meta
+}
+
+// applyOrientation rotates/flips img according to the EXIF Orientation tag
+// (values 1-8: identity, mirror-H, rotate-180, mirror-V, transpose,
+// rotate-90-CW, transverse, rotate-90-CCW).
+func applyOrientation(img image.Image, orientation int) image.Image {
+	switch orientation {
+	case 2:
+		return FlipH(img)
+	case 3:
+		return Rotate180(img)
+	case 4:
+		return FlipV(img)
+	case 5:
+		return Transpose(img)
+	case 6:
+		return Rotate90(img)
+	case 7:
+		return Rotate180(Transpose(img))
+	case 8:
+		return Rotate270(img)
+	default:
+		return img
+	}
+}
@@ -34,23 +34,15 @@ func ToNRGBA(img image.Image) *image.NRGBA {
 
 // ReadImage reads an image from the specified path and returns it as an image.Image.
 // If the image cannot be decoded, it returns nil and logs the error.
-// The function now uses the ToNRGBA function to ensure the image is in NRGBA format.
+// The function uses ReadImageWithMeta internally, so JPEGs with an EXIF
+// Orientation tag are auto-rotated before being returned.
 func ReadImage(path string) image.Image {
-	inputFile, err := os.Open(path)
+	img, _, err := ReadImageWithMeta(path)
 	if err != nil {
-		log.Printf("Failed to open image: %s, error: %v", path, err)
+		log.Printf("Failed to read image: %s, error: %v", path, err)
 		return nil
 	}
-	defer inputFile.Close()
-
-	img, _, err := image.Decode(inputFile)
-	if err != nil {
-		log.Printf("Failed to decode image: %s, error: %v", path, err)
-		return nil
-	}
-
-	// Convert to NRGBA if the image is not already in that format
-	return ToNRGBA(img)
+	return img
 }
 
 // WriteImage writes an image to the given path in the specified format.
@@ -127,3 +127,64 @@ func TestGaussianBlurDoesNotPanic(t *testing.T) {
 		t.Errorf("Blurred center pixel R out of expected range: %.2f", p.R)
 	}
 }
+
+func TestGaussianBlurWithOptsProcessesEdges(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 5, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			img.Set(x, y, color.NRGBA{R: 100, G: 100, B: 100, A: 255})
+		}
+	}
+	matrix := ImageToRGBAMatrix(img)
+
+	matrix.GaussianBlurWithOpts(GaussianBlurOpts{KernelSize: 3, Sigma: 1.0, Border: BorderReflect})
+
+	// With BorderReflect the taps past the edge read real pixels, so the
+	// corner of a flat image keeps its value instead of darkening as it
+	// does under BorderZero.
+	corner := matrix.Data[0][0]
+	if corner.R < 90 || corner.R > 110 {
+		t.Errorf("Blurred corner pixel R out of expected range: %.2f", corner.R)
+	}
+}
+
+func TestGaussianBlurBorderZeroDarkensEdges(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 5, 5))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 200, B: 200, A: 255})
+		}
+	}
+	matrix := ImageToRGBAMatrix(img)
+
+	matrix.GaussianBlurWithOpts(GaussianBlurOpts{KernelSize: 3, Sigma: 1.0, Border: BorderZero})
+
+	corner := matrix.Data[0][0]
+	if corner.R >= 200 {
+		t.Errorf("BorderZero corner pixel R = %.2f, want < 200 (out-of-range taps should contribute zero, not be renormalized away)", corner.R)
+	}
+}
+
+func TestRemapIndexBorderModes(t *testing.T) {
+	cases := []struct {
+		name      string
+		i, length int
+		mode      BorderMode
+		wantIdx   int
+		wantOK    bool
+	}{
+		{"clamp below", -1, 5, BorderClamp, 0, true},
+		{"clamp above", 5, 5, BorderClamp, 4, true},
+		{"reflect below", -1, 5, BorderReflect, 0, true},
+		{"wrap below", -1, 5, BorderWrap, 4, true},
+		{"zero below", -1, 5, BorderZero, 0, false},
+		{"in range", 2, 5, BorderClamp, 2, true},
+	}
+	for _, c := range cases {
+		idx, ok := remapIndex(c.i, c.length, c.mode)
+		if idx != c.wantIdx || ok != c.wantOK {
+			t.Errorf("%s: remapIndex(%d, %d) = (%d, %v), want (%d, %v)",
+				c.name, c.i, c.length, idx, ok, c.wantIdx, c.wantOK)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package imageprocessing
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// makeLMarker returns a 2x3 image with a distinct color in each corner so
+// rotations/flips can be verified by checking where each corner landed.
+func makeLMarker() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 3))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255}) // top-left: red
+	img.Set(1, 0, color.NRGBA{G: 255, A: 255}) // top-right: green
+	img.Set(0, 2, color.NRGBA{B: 255, A: 255}) // bottom-left: blue
+	return img
+}
+
+func TestRotate90MovesTopLeftToTopRight(t *testing.T) {
+	img := makeLMarker()
+	rotated := Rotate90(img)
+
+	if rotated.Bounds().Dx() != 3 || rotated.Bounds().Dy() != 2 {
+		t.Fatalf("Expected rotated bounds 3x2, got %dx%d", rotated.Bounds().Dx(), rotated.Bounds().Dy())
+	}
+	r, _, _, _ := rotated.At(2, 0).RGBA()
+	if uint8(r>>8) != 255 {
+		t.Errorf("Expected top-left red pixel to land at top-right after a 90 degree clockwise rotation")
+	}
+}
+
+func TestRotate180FlipsBothAxes(t *testing.T) {
+	img := makeLMarker()
+	rotated := Rotate180(img)
+
+	r, _, _, _ := rotated.At(1, 2).RGBA()
+	if uint8(r>>8) != 255 {
+		t.Errorf("Expected top-left red pixel to land at bottom-right after a 180 degree rotation")
+	}
+}
+
+func TestFlipHMirrorsLeftRight(t *testing.T) {
+	img := makeLMarker()
+	flipped := FlipH(img)
+
+	r, _, _, _ := flipped.At(1, 0).RGBA()
+	if uint8(r>>8) != 255 {
+		t.Errorf("Expected top-left red pixel to land at top-right after a horizontal flip")
+	}
+}
+
+func TestFlipVMirrorsTopBottom(t *testing.T) {
+	img := makeLMarker()
+	flipped := FlipV(img)
+
+	r, _, _, _ := flipped.At(0, 2).RGBA()
+	if uint8(r>>8) != 255 {
+		t.Errorf("Expected top-left red pixel to land at bottom-left after a vertical flip")
+	}
+}
+
+func TestTransposeSwapsAxes(t *testing.T) {
+	img := makeLMarker()
+	transposed := Transpose(img)
+
+	if transposed.Bounds().Dx() != 3 || transposed.Bounds().Dy() != 2 {
+		t.Fatalf("Expected transposed bounds 3x2, got %dx%d", transposed.Bounds().Dx(), transposed.Bounds().Dy())
+	}
+	_, g, _, _ := transposed.At(0, 1).RGBA()
+	if uint8(g>>8) != 255 {
+		t.Errorf("Expected top-right green pixel to land at (0,1) after a transpose")
+	}
+}
+
+func TestApplyOrientationIdentity(t *testing.T) {
+	img := makeLMarker()
+	out := applyOrientation(img, 1)
+	if out.Bounds() != img.Bounds() {
+		t.Errorf("Expected orientation 1 to be the identity transform")
+	}
+}
+
+func TestReadMetadataReturnsNilWithoutExif(t *testing.T) {
+	if readMetadata("does_not_exist.jpg") != nil {
+		t.Errorf("Expected nil metadata for a missing file")
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	imageprocessing "goroutines_pipeline/image_processing"
@@ -14,18 +15,21 @@ type Job struct {
 	InputPath string
 	Image     image.Image
 	OutPath   string
+	Err       error
 }
 
 type Status struct {
 	Success bool
 	Path    string
+	Err     error
 }
 
-func loadImage(paths []string) <-chan Job {
+func loadImage(ctx context.Context, paths []string) <-chan Job {
 	out := make(chan Job)
 	go func() {
 		// For each input path create a job and add it to
 		// the out channel
+		defer close(out)
 		for _, p := range paths {
 			job := Job{
 				// Fixed: Now keeping the original subdirectories structure
@@ -33,78 +37,61 @@ func loadImage(paths []string) <-chan Job {
 				OutPath:   filepath.Join("images", "output", filepath.Base(p)),
 			}
 			job.Image = imageprocessing.ReadImage(p)
-			out <- job
-		}
-		close(out)
-	}()
-	return out
-}
-
-func resize(input <-chan Job) <-chan Job {
-	out := make(chan Job)
-	go func() {
-		// For each input job, create a new job after resize and add it to
-		// the out channel
-		for job := range input { // Read from the channel
-			job.Image = imageprocessing.Resize(job.Image, 0.5)
-			out <- job
-		}
-		close(out)
-	}()
-	return out
-}
-
-func convertToGrayscale(input <-chan Job) <-chan Job {
-	out := make(chan Job)
-	go func() {
-		for job := range input { // Read from the channel
-			job.Image = imageprocessing.Grayscale(job.Image)
-			out <- job
-		}
-		close(out)
-	}()
-	return out
-}
-
-func saveImage(input <-chan Job) <-chan Status {
-	out := make(chan Status)
-	go func() {
-		for job := range input {
-			err := imageprocessing.WriteImage(job.OutPath, job.Image)
-
-			stat := Status{
-				Success: err == nil,
-				Path:    job.InputPath,
+			if job.Image == nil {
+				job.Err = fmt.Errorf("failed to load image: %s", p)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- job:
 			}
-			out <- stat
 		}
-		close(out)
 	}()
 	return out
 }
 
-func adjustAlpha(input <-chan Job, factor float64) <-chan Job {
-	out := make(chan Job)
-	go func() {
-		for job := range input {
-			job.Image = imageprocessing.AdjustAlpha(job.Image, factor)
-			out <- job
-		}
-		close(out)
-	}()
-	return out
+// brightnessStage returns a Stage that increases each job's brightness by
+// delta.
+func brightnessStage(delta int) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					job.Image = imageprocessing.IncreaseBrightness(job.Image, delta)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
 }
 
-func increaseBrightness(input <-chan Job, delta int) <-chan Job {
-	out := make(chan Job)
-	go func() {
-		for job := range input {
-			job.Image = imageprocessing.IncreaseBrightness(job.Image, delta)
-			out <- job
-		}
-		close(out)
-	}()
-	return out
+// alphaStage returns a Stage that adjusts each job's alpha channel by
+// factor.
+func alphaStage(factor float64) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					job.Image = imageprocessing.AdjustAlpha(job.Image, factor)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
 }
 
 // getImagePaths returns a slice of image paths from the given directory
@@ -159,20 +146,28 @@ func runPipelineSequentially(paths []string) {
 }
 
 func runPipelineWithGoroutines(paths []string) {
-	channel1 := loadImage(paths)
-	channel2 := resize(channel1)
-	channel3 := convertToGrayscale(channel2)
-	channel4 := increaseBrightness(channel3, 40)
-	channel5 := adjustAlpha(channel4, 0.5)
-	writeResults := saveImage(channel5)
-
-	for result := range writeResults {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pipeline := NewPipeline().
+		Add("resize", Resize(0.5)).
+		Add("grayscale", Grayscale()).
+		Add("brightness", brightnessStage(40)).
+		Add("alpha", alphaStage(0.5)).
+		Add("save", WriteAs("")).
+		Add("blurhash", BlurHashSidecar(4, 3))
+
+	for result := range pipeline.Run(ctx, loadImage(ctx, paths)) {
 		if result.Success {
 			fmt.Println("Goroutine Success!", result.Path)
 		} else {
-			fmt.Println("Goroutine Failed!", result.Path)
+			fmt.Println("Goroutine Failed!", result.Path, result.Err)
 		}
 	}
+
+	for _, m := range pipeline.Metrics() {
+		fmt.Printf("stage %-10s jobs=%d total=%s\n", m.Name, m.Count, m.TotalDuration)
+	}
 }
 
 func runMatrixTests(paths []string) {
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	imageprocessing "goroutines_pipeline/image_processing"
+	"os"
+	"sync"
+	"time"
+)
+
+// stageBufferSize is the capacity of the channels connecting pipeline
+// stages. A bounded buffer gives the pipeline backpressure: a slow stage
+// fills its input channel and upstream stages block on send instead of
+// piling up work in memory.
+const stageBufferSize = 8
+
+// Stage transforms a stream of jobs, reading from in and producing a new
+// output channel. A Stage is expected to close its output channel once in
+// is drained and closed, or as soon as ctx is canceled, whichever comes
+// first.
+type Stage func(ctx context.Context, in <-chan Job) <-chan Job
+
+// pipelineStage is one named step registered with a Pipeline, along with
+// how many worker goroutines should run it concurrently.
+type pipelineStage struct {
+	name   string
+	fn     Stage
+	fanout int
+}
+
+// StageMetrics reports how many jobs a stage processed and how long it
+// spent processing them in total.
+type StageMetrics struct {
+	Name          string
+	Count         int
+	TotalDuration time.Duration
+}
+
+// Pipeline is a builder for a sequence of Stages. Stages run concurrently
+// with each other, connected by buffered channels; Fanout stages run
+// multiple worker goroutines against the same input.
+type Pipeline struct {
+	stages  []pipelineStage
+	mu      sync.Mutex
+	metrics map[string]*StageMetrics
+}
+
+// NewPipeline returns an empty Pipeline ready to have stages added to it.
+func NewPipeline() *Pipeline {
+	return &Pipeline{metrics: make(map[string]*StageMetrics)}
+}
+
+// Add appends a stage that runs as a single goroutine, identified by name
+// in the pipeline's metrics.
+func (p *Pipeline) Add(name string, stage Stage) *Pipeline {
+	p.stages = append(p.stages, pipelineStage{name: name, fn: stage, fanout: 1})
+	return p
+}
+
+// Fanout appends a stage that runs as n worker goroutines, all reading from
+// the same input channel and merging their output back into a single
+// downstream channel.
+func (p *Pipeline) Fanout(n int, stage Stage) *Pipeline {
+	name := fmt.Sprintf("fanout-%d-workers-%d", len(p.stages), n)
+	p.stages = append(p.stages, pipelineStage{name: name, fn: stage, fanout: n})
+	return p
+}
+
+// Run wires up every registered stage in order and starts the pipeline,
+// returning a channel of per-job Status. Processing stops early if ctx is
+// canceled: each stage stops forwarding and exits, so jobs still in flight
+// are dropped rather than leaving goroutines blocked on a send.
+func (p *Pipeline) Run(ctx context.Context, jobs <-chan Job) <-chan Status {
+	cur := jobs
+	for _, st := range p.stages {
+		cur = p.runStage(ctx, st, cur)
+	}
+
+	out := make(chan Status, stageBufferSize)
+	go func() {
+		defer close(out)
+		for job := range cur {
+			status := Status{Success: job.Err == nil, Path: job.InputPath, Err: job.Err}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- status:
+			}
+		}
+	}()
+	return out
+}
+
+// Metrics returns a snapshot of the per-stage counters recorded so far.
+func (p *Pipeline) Metrics() []StageMetrics {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]StageMetrics, 0, len(p.metrics))
+	for _, m := range p.metrics {
+		out = append(out, *m)
+	}
+	return out
+}
+
+// runStage wires a single registered stage into the pipeline, spawning
+// st.fanout instrumented workers against the shared input and merging their
+// outputs when fanout is greater than one.
+func (p *Pipeline) runStage(ctx context.Context, st pipelineStage, in <-chan Job) <-chan Job {
+	if st.fanout <= 1 {
+		return p.instrument(ctx, st.name, st.fn, in)
+	}
+
+	outs := make([]<-chan Job, st.fanout)
+	for i := 0; i < st.fanout; i++ {
+		outs[i] = p.instrument(ctx, st.name, st.fn, in)
+	}
+	return mergeJobs(ctx, outs...)
+}
+
+// instrument wraps stage so that the time each job spends between entering
+// and leaving it is recorded against name. It relies on the wrapped stage
+// processing jobs in the order it receives them, which holds for every
+// stage in this package.
+func (p *Pipeline) instrument(ctx context.Context, name string, stage Stage, in <-chan Job) <-chan Job {
+	annotatedIn := make(chan Job, stageBufferSize)
+	var mu sync.Mutex
+	var starts []time.Time
+
+	go func() {
+		defer close(annotatedIn)
+		for job := range in {
+			mu.Lock()
+			starts = append(starts, time.Now())
+			mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return
+			case annotatedIn <- job:
+			}
+		}
+	}()
+
+	rawOut := stage(ctx, annotatedIn)
+	out := make(chan Job, stageBufferSize)
+	go func() {
+		defer close(out)
+		for job := range rawOut {
+			mu.Lock()
+			var start time.Time
+			if len(starts) > 0 {
+				start = starts[0]
+				starts = starts[1:]
+			}
+			mu.Unlock()
+			if !start.IsZero() {
+				p.record(name, time.Since(start))
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- job:
+			}
+		}
+	}()
+	return out
+}
+
+// record adds a completed job's processing time to the running totals for
+// the named stage.
+func (p *Pipeline) record(name string, d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m, ok := p.metrics[name]
+	if !ok {
+		m = &StageMetrics{Name: name}
+		p.metrics[name] = m
+	}
+	m.Count++
+	m.TotalDuration += d
+}
+
+// mergeJobs fans multiple job channels into one, closing the merged channel
+// once every input channel has closed or ctx is canceled.
+func mergeJobs(ctx context.Context, chans ...<-chan Job) <-chan Job {
+	out := make(chan Job, stageBufferSize)
+	var wg sync.WaitGroup
+	wg.Add(len(chans))
+
+	for _, c := range chans {
+		go func(c <-chan Job) {
+			defer wg.Done()
+			for job := range c {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}(c)
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Resize returns a Stage that scales each job's image by the given factor.
+func Resize(scale float64) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					job.Image = imageprocessing.Resize(job.Image, scale)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// Grayscale returns a Stage that converts each job's image to grayscale.
+func Grayscale() Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					job.Image = imageprocessing.Grayscale(job.Image)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// Blur returns a Stage that applies a Gaussian blur of the given kernel
+// size and sigma to each job's image.
+func Blur(size int, sigma float64) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					matrix := imageprocessing.ImageToRGBAMatrix(job.Image)
+					matrix.GaussianBlur(size, sigma)
+					job.Image = matrix.RGBAMatrixToImage(matrix)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// Convolve returns a Stage that applies the given kernel to each job's
+// image, using clamped border handling.
+func Convolve(kernel imageprocessing.Kernel) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					matrix := imageprocessing.ImageToRGBAMatrix(job.Image)
+					convolved := matrix.Convolve(kernel, imageprocessing.ConvolveOpts{Border: imageprocessing.BorderClamp})
+					job.Image = convolved.RGBAMatrixToImage(convolved)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// BlurHashSidecar returns a Stage that computes a BlurHash placeholder for
+// each job's image and writes it alongside job.OutPath as a
+// "<name>.blurhash" sidecar file, for use as a placeholder in web UIs.
+func BlurHashSidecar(xComponents, yComponents int) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					hash, err := imageprocessing.BlurHash(job.Image, xComponents, yComponents)
+					if err != nil {
+						job.Err = err
+					} else if err := os.WriteFile(job.OutPath+".blurhash", []byte(hash), 0644); err != nil {
+						job.Err = err
+					}
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// WriteAs returns a Stage that writes each job's image to job.OutPath in
+// the given format, or infers the format from OutPath's extension if
+// format is empty. Jobs that already carry an error are passed through
+// without being written.
+func WriteAs(format string) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				if job.Err == nil {
+					if err := imageprocessing.WriteImage(job.OutPath, job.Image, format); err != nil {
+						job.Err = err
+					}
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 // benchmark tests for two different implementations of the image processing pipeline
@@ -19,3 +24,145 @@ func BenchmarkRunPipelineSequentially(b *testing.B) {
 		runPipelineSequentially(imagePaths)
 	}
 }
+
+// passthroughStage returns a Stage that forwards every job unchanged,
+// counting how many jobs it has seen in seen.
+func passthroughStage(seen *int32) Stage {
+	return func(ctx context.Context, in <-chan Job) <-chan Job {
+		out := make(chan Job, stageBufferSize)
+		go func() {
+			defer close(out)
+			for job := range in {
+				atomic.AddInt32(seen, 1)
+				select {
+				case <-ctx.Done():
+					return
+				case out <- job:
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// jobsOf mimics loadImage's exact shape: an unbuffered producer goroutine
+// that selects on ctx.Done() around its send, so tests built on it exercise
+// the same cancellation path the real pipeline entry point does.
+func jobsOf(ctx context.Context, paths ...string) <-chan Job {
+	out := make(chan Job)
+	go func() {
+		defer close(out)
+		for _, p := range paths {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- Job{InputPath: p, OutPath: p}:
+			}
+		}
+	}()
+	return out
+}
+
+// TestPipelineFanoutMergesAllWorkerOutput runs a Fanout stage with several
+// workers and checks that every job submitted comes back out exactly once,
+// regardless of which worker happened to process it.
+func TestPipelineFanoutMergesAllWorkerOutput(t *testing.T) {
+	const jobCount = 50
+	paths := make([]string, jobCount)
+	for i := range paths {
+		paths[i] = fmt.Sprintf("job-%d", i)
+	}
+
+	var seen int32
+	pipeline := NewPipeline().Fanout(4, passthroughStage(&seen))
+
+	ctx := context.Background()
+	results := pipeline.Run(ctx, jobsOf(ctx, paths...))
+
+	got := make(map[string]int, jobCount)
+	for status := range results {
+		got[status.Path]++
+	}
+
+	if int32(jobCount) != seen {
+		t.Fatalf("fanout workers saw %d jobs, want %d", seen, jobCount)
+	}
+	if len(got) != jobCount {
+		t.Fatalf("Run returned %d distinct jobs, want %d", len(got), jobCount)
+	}
+	for path, count := range got {
+		if count != 1 {
+			t.Errorf("job %q came back %d times, want 1", path, count)
+		}
+	}
+}
+
+// TestPipelineMetricsAggregatesAcrossFanoutWorkers checks that Metrics sums
+// the counts from every worker of a fanned-out stage under one stage name.
+func TestPipelineMetricsAggregatesAcrossFanoutWorkers(t *testing.T) {
+	const jobCount = 20
+	paths := make([]string, jobCount)
+	for i := range paths {
+		paths[i] = string(rune('a' + i%26))
+	}
+
+	var seen int32
+	pipeline := NewPipeline().Fanout(3, passthroughStage(&seen))
+
+	ctx := context.Background()
+	for range pipeline.Run(ctx, jobsOf(ctx, paths...)) {
+	}
+
+	metrics := pipeline.Metrics()
+	if len(metrics) != 1 {
+		t.Fatalf("Metrics returned %d entries, want 1", len(metrics))
+	}
+	if metrics[0].Count != jobCount {
+		t.Errorf("Metrics()[0].Count = %d, want %d", metrics[0].Count, jobCount)
+	}
+}
+
+// TestPipelineRunStopsOnCancellation cancels the context mid-flight and
+// checks that Run's output channel closes promptly and that no stage
+// goroutine is left blocked sending to a channel nobody drains anymore.
+func TestPipelineRunStopsOnCancellation(t *testing.T) {
+	const jobCount = 200
+	paths := make([]string, jobCount)
+	for i := range paths {
+		paths[i] = string(rune('a' + i%26))
+	}
+
+	var seen int32
+	pipeline := NewPipeline().
+		Add("stage-a", passthroughStage(&seen)).
+		Add("stage-b", passthroughStage(&seen))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := pipeline.Run(ctx, jobsOf(ctx, paths...))
+
+	// Read a single result, then cancel; stageBufferSize is far smaller
+	// than jobCount so later stages are still blocked on sends at that
+	// point.
+	<-results
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range results {
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run's output channel did not close within 2s of cancellation")
+	}
+
+	before := runtime.NumGoroutine()
+	time.Sleep(50 * time.Millisecond)
+	after := runtime.NumGoroutine()
+	if after > before {
+		t.Errorf("goroutine count grew from %d to %d after cancellation settled; stage goroutines may be leaking", before, after)
+	}
+}